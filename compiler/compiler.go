@@ -4,6 +4,8 @@ import (
 	"fmt"
 
 	"github.com/srax47/godzilla/ast"
+	"github.com/srax47/godzilla/compiler/emit"
+	"github.com/srax47/godzilla/compiler/ir"
 	"github.com/srax47/godzilla/runtime"
 	"github.com/srax47/godzilla/source"
 	"github.com/srax47/godzilla/utils"
@@ -13,8 +15,9 @@ func Compile(f *ast.File) *source.Code {
 	code := source.NewCode()
 
 	c := &compiler{
-		code: code,
-		ctx:  runtime.NewDefaultContext(),
+		code:       code,
+		ctx:        runtime.NewDefaultContext(),
+		sourceFile: f.Filename,
 	}
 	c.compile(f)
 
@@ -22,15 +25,45 @@ func Compile(f *ast.File) *source.Code {
 }
 
 type compiler struct {
-	code *source.Code
-	ctx  *runtime.Context
+	code       *source.Code
+	ctx        *runtime.Context
+	tempVar    int
+	bindings   map[*ast.Identifier]binding
+	exports    map[string]string
+	sourceFile string
+	// inFunction is true while compiling the body of a function literal.
+	// Declarations only get mirrored onto the shared global object
+	// (global.DefineProperty) at the top level - a local var or a nested
+	// function declaration must stay local to its Go closure, or it
+	// clobbers/shadows anything of the same name on the real JS global
+	// object.
+	inFunction bool
+	// inModule is true for a compiler compiling an ES module body (see
+	// CompileModule in modules.go). A module's top-level bindings are
+	// module-scoped, not script-global - they're visible to other modules
+	// only via explicit export, never by being mirrored onto the shared
+	// global object the way a plain script's top-level var/function is.
+	inModule bool
+}
+
+// nextTempVar returns a fresh, collision-free Go identifier for codegen that
+// needs to stash an intermediate value (e.g. a switch discriminant) in a
+// local variable.
+func (c *compiler) nextTempVar(prefix string) string {
+	c.tempVar++
+	return fmt.Sprintf("__%s%d", prefix, c.tempVar)
 }
 
 func (c *compiler) compile(f *ast.File) {
+	res := newResolver()
+	res.resolveProgram(f.Program)
+	c.bindings = res.bindings
+
 	c.compileProgram(f.Program)
 }
 
 func (c *compiler) compileProgram(p *ast.Program) {
+	c.writeHoistedVars(p.Body)
 	for _, s := range p.Body {
 		c.writeLineNo(s)
 		c.compileStatement(s)
@@ -46,6 +79,36 @@ func (c *compiler) compileStatement(s ast.Statement) {
 		c.compileExpressionStatement(v)
 	case *ast.VariableDeclaration:
 		c.compileVariableDeclaration(v)
+	case *ast.IfStatement:
+		c.compileIfStatement(v)
+	case *ast.BlockStatement:
+		c.compileBlockStatement(v)
+	case *ast.WhileStatement:
+		c.compileWhileStatement(v)
+	case *ast.DoWhileStatement:
+		c.compileDoWhileStatement(v)
+	case *ast.ForStatement:
+		c.compileForStatement(v)
+	case *ast.ForInStatement:
+		c.compileForInStatement(v)
+	case *ast.BreakStatement:
+		c.compileBreakStatement(v)
+	case *ast.ContinueStatement:
+		c.compileContinueStatement(v)
+	case *ast.ReturnStatement:
+		c.compileReturnStatement(v)
+	case *ast.SwitchStatement:
+		c.compileSwitchStatement(v)
+	case *ast.FunctionDeclaration:
+		c.compileFunctionDeclaration(v)
+	case *ast.ImportDeclaration:
+		c.compileImportDeclaration(v)
+	case *ast.ExportNamedDeclaration:
+		c.compileExportNamedDeclaration(v)
+	case *ast.ExportDefaultDeclaration:
+		c.compileExportDefaultDeclaration(v)
+	case *ast.ExportAllDeclaration:
+		c.compileExportAllDeclaration(v)
 	default:
 		panic("unknown statement type " + utils.TypeOf(v))
 	}
@@ -62,18 +125,22 @@ func (c *compiler) compileVariableDeclaration(vd *ast.VariableDeclaration) {
 	}
 }
 
+// compileVariableDeclarator only assigns - the Go variable itself was
+// already declared once up front by writeHoistedVars, matching where
+// resolver.go hoists the binding to. Only a script-top-level declaration
+// also becomes a global property; a local, or a module-level binding (see
+// inModule), only ever lives in its own Go closure.
 func (c *compiler) compileVariableDeclarator(vd *ast.VariableDeclarator) {
 	name := vd.ID.Name
 
-	c.code.WriteLine(fmt.Sprintf("var %s Object", name))
-	c.code.WriteLine(fmt.Sprintf("_ = %s", name))
 	if vd.Init != nil {
 		c.code.Write(fmt.Sprintf("%s = ", name))
 		c.compileExpression(vd.Init)
 		c.code.WriteLine("")
 	}
-	c.code.Write(fmt.Sprintf(`global.DefineProperty("%s", %s)`, name, name))
-	c.defineVar(name)
+	if !c.inFunction && !c.inModule {
+		c.code.Write(fmt.Sprintf(`global.DefineProperty("%s", %s)`, name, name))
+	}
 }
 
 // expressions
@@ -94,82 +161,83 @@ func (c *compiler) compileExpression(e ast.Expression) {
 		c.compileStringLiteral(v)
 	case *ast.NumericLiteral:
 		c.compileNumericLiteral(v)
+	case *ast.FunctionExpression:
+		c.compileFunctionExpression(v)
+	case *ast.ArrowFunctionExpression:
+		c.compileArrowFunctionExpression(v)
+	case *ast.TemplateLiteral:
+		c.compileTemplateLiteral(v)
+	case *ast.ArrayExpression:
+		c.compileArrayExpression(v)
+	case *ast.ObjectExpression:
+		c.compileObjectExpression(v)
+	case *ast.UpdateExpression:
+		c.compileUpdateExpression(v)
+	case *ast.UnaryExpression:
+		c.compileUnaryExpression(v)
 	default:
 		panic("unknown expression type " + utils.TypeOf(v))
 	}
 }
 
 func (c *compiler) compileCallExpression(ce *ast.CallExpression) {
-	c.compileExpression(ce.Callee)
-	c.code.Write("([]Object{")
-	for i, arg := range ce.Arguments {
-		c.compileExpression(arg)
-		if i != len(ce.Arguments)-1 {
-			c.code.Write(", ")
-		}
-	}
-	c.code.Write("})\n")
-}
-
-// TODO: ignoring computed value for now
-func (c *compiler) compileMemberExpression(me *ast.MemberExpression) {
-	if me.Computed {
-		panic("computed MemberExpression is not supported")
-	}
-
-	if builtInFunc := c.getBuiltinFunc(me.Object, me.Property); builtInFunc == "" {
-		c.compileExpression(me.Object)
-		c.code.Write(".")
-		c.compileExpression(me.Property)
-	} else {
-		c.code.Write(builtInFunc)
-	}
+	emit.Expression(c.code, ir.Fold(c.lowerExpression(ce)))
+	c.code.Write("\n")
 }
 
-func (c *compiler) compileAssignmentExpression(ae *ast.AssignmentExpression) {
-	c.compileExpression(ae.Left)
-	c.code.Write(fmt.Sprintf(" %s ", ae.Operator))
-	c.compileExpression(ae.Right)
-}
+// compileMemberExpression and compileAssignmentExpression live in members.go,
+// alongside the rest of the computed-access/array/object-literal codegen
+// they need to cooperate with.
 
+// compileBinaryExpression goes through the IR (see compiler/ir, compiler/emit)
+// rather than writing Go source directly, so constant subexpressions (e.g.
+// `2 + 2`) fold at compile time instead of emitting a runtime addition.
 func (c *compiler) compileBinaryExpression(be *ast.BinaryExpression) {
-	c.compileExpression(be.Left)
-	c.code.Write(fmt.Sprintf(" %s ", be.Operator))
-	c.compileExpression(be.Right)
+	emit.Expression(c.code, ir.Fold(c.lowerExpression(be)))
 }
 
+// compileIdentifier emits a direct Go variable reference (for Local and Free
+// bindings - see resolver.go), a pointer dereference (for an Import binding),
+// or a global property lookup. Free
+// bindings compile the same as Local ones: Go closures already capture
+// enclosing locals by reference, which is exactly the semantics a resolved
+// Free binding needs.
 func (c *compiler) compileIdentifier(i *ast.Identifier) {
-	if c.isVarDefined(i.Name) {
-		c.code.Write(i.Name)
-	} else {
-		c.code.Write(fmt.Sprintf(`global.GetProperty("%s")`, i.Name))
-	}
+	c.code.Write(c.identifierGoExpr(i))
 }
 
 func (c *compiler) compileStringLiteral(s *ast.StringLiteral) {
-	c.code.Write(fmt.Sprintf(`JSString("%s")`, s.Value))
+	emit.Expression(c.code, c.lowerExpression(s))
 }
 
 func (c *compiler) compileNumericLiteral(n *ast.NumericLiteral) {
-	c.code.Write(fmt.Sprintf(`JSNumber(%f)`, n.Value))
+	emit.Expression(c.code, c.lowerExpression(n))
 }
 
-func (c *compiler) writeLineNo(node ast.Node) {
-	c.code.WriteLine(fmt.Sprintf(`// line %d: %s`, node.GetAttr().Loc.Start.Line, node))
-}
-
-// defineVar defines the var when the compiler sees it
-// This is used for optimizing compiled code for direct reference of var
-// FIXME: defined var is cached in global context for now
-func (c *compiler) defineVar(name string) {
-	// FIXME: Prop value is a dummpy obj for now
-	c.ctx.Global.DefineProperty(name, &runtime.JSObject{})
+// compileTemplateLiteral interleaves the literal text between `${...}`
+// interpolations (Quasis) with the compiled interpolated expressions
+// (Expressions) and concatenates the result via the runtime, the same way
+// the spec defines template literal evaluation. See lowerExpression for the
+// actual Quasis/Expressions interleaving.
+func (c *compiler) compileTemplateLiteral(tl *ast.TemplateLiteral) {
+	emit.Expression(c.code, ir.Fold(c.lowerExpression(tl)))
 }
 
-// FIXME: Using global context for now
-func (c *compiler) isVarDefined(name string) bool {
-	_, err := c.ctx.Global.GetProperty(name)
-	return err == nil
+// writeLineNo marks the upcoming generated line as corresponding to node's
+// source position via a `//line` directive, which the Go compiler itself
+// understands - so a panic in the compiled output reports the originating
+// .js file and line rather than the generated Go file.
+//
+// TODO: this is not a real source map. A Source Map v3 (VLQ-encoded
+// generated-position -> source-position mappings, consumable by browser
+// devtools) needs source.Code to track generated line/column as it writes
+// and expose that encoding, which doesn't exist yet - that's a source
+// package change, not a compiler one. Until it lands, debugging a compiled
+// module in-browser only gets line-level fidelity from the directive
+// above, nothing column-accurate and nothing devtools can load as a map.
+func (c *compiler) writeLineNo(node ast.Node) {
+	loc := node.GetAttr().Loc
+	c.code.WriteLine(fmt.Sprintf("//line %s:%d", c.sourceFile, loc.Start.Line))
 }
 
 func (c *compiler) getBuiltinFunc(objExp, propExp ast.Expression) string {
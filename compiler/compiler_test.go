@@ -0,0 +1,84 @@
+package compiler
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/srax47/godzilla/ast"
+	"github.com/srax47/godzilla/source"
+)
+
+// for (x in obj) must assign the enumerated key into the existing binding
+// for x, not shadow it with a fresh loop-scoped `:=` - otherwise reading x
+// after the loop (or a closure capturing it) never reflects anything
+// enumerated.
+func TestForInAssignsExistingBinding(t *testing.T) {
+	xDecl := &ast.VariableDeclaration{Kind: "var", Declarations: []*ast.VariableDeclarator{
+		{ID: &ast.Identifier{Name: "x"}},
+	}}
+	xRef := &ast.Identifier{Name: "x"}
+	forIn := &ast.ForInStatement{
+		Left:  xRef,
+		Right: &ast.Identifier{Name: "obj"},
+		Body:  &ast.BlockStatement{},
+	}
+	prog := &ast.Program{Body: []ast.Statement{xDecl, forIn}}
+
+	res := newResolver()
+	res.resolveProgram(prog)
+
+	c := &compiler{code: source.NewCode(), bindings: res.bindings}
+	c.writeHoistedVars(prog.Body)
+	c.compileForInStatement(forIn)
+
+	out := c.code.String()
+	if !strings.Contains(out, "x = ") {
+		t.Fatalf("expected the enumerated key to be assigned into the resolved binding for x, got:\n%s", out)
+	}
+	if strings.Contains(out, "x := ") {
+		t.Fatalf("for-in must not shadow x with a fresh loop-scoped declaration, got:\n%s", out)
+	}
+}
+
+// A labeled break inside a switch case means "exit an enclosing labeled
+// loop", not "end this case" - it must still compile to a Go break, unlike
+// a bare break which is dropped since falling out of the Go case already
+// has the same effect.
+func TestSwitchCaseKeepsLabeledBreak(t *testing.T) {
+	cs := &ast.SwitchCase{Consequent: []ast.Statement{
+		&ast.BreakStatement{Label: &ast.Identifier{Name: "outer"}},
+	}}
+
+	c := &compiler{code: source.NewCode()}
+	c.compileSwitchCaseBody(cs, false)
+
+	out := c.code.String()
+	if !strings.Contains(out, "break outer") {
+		t.Fatalf("expected a labeled break to still be emitted, got:\n%s", out)
+	}
+}
+
+// An arrow function's concise (non-block) body is an expression, not a
+// statement - every identifier inside it, including the arrow's own
+// parameters, has to resolve through resolveExpression or it silently
+// falls back to a global lookup.
+func TestArrowConciseBodyResolvesParams(t *testing.T) {
+	a := &ast.Identifier{Name: "a"}
+	b := &ast.Identifier{Name: "b"}
+	aRef := &ast.Identifier{Name: "a"}
+	bRef := &ast.Identifier{Name: "b"}
+	arrow := &ast.ArrowFunctionExpression{
+		Params: []*ast.Identifier{a, b},
+		Body:   &ast.BinaryExpression{Operator: "+", Left: aRef, Right: bRef},
+	}
+
+	res := newResolver()
+	res.resolveExpression(arrow)
+
+	if bnd, ok := res.bindings[aRef]; !ok || bnd.kind == BindingGlobal {
+		t.Fatalf("expected parameter a to resolve as a local inside the concise body, got %+v", res.bindings[aRef])
+	}
+	if bnd, ok := res.bindings[bRef]; !ok || bnd.kind == BindingGlobal {
+		t.Fatalf("expected parameter b to resolve as a local inside the concise body, got %+v", res.bindings[bRef])
+	}
+}
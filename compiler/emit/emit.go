@@ -0,0 +1,114 @@
+// Package emit walks compiler/ir nodes and renders them as Go source onto a
+// source.Code writer. It's the first (and so far only) backend for the IR;
+// a bytecode VM or WASM backend would live alongside this package and
+// consume the exact same ir.Node tree.
+package emit
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/srax47/godzilla/compiler/ir"
+	"github.com/srax47/godzilla/source"
+)
+
+// Expression renders n as a single Go expression onto code, with no
+// trailing newline - matching the convention compiler.compileExpression
+// already uses for inline composition (callers that need a statement add
+// their own WriteLine).
+func Expression(code *source.Code, n ir.Node) {
+	switch v := n.(type) {
+	case *ir.Const:
+		code.Write(constLiteral(v))
+	case *ir.Load:
+		code.Write(v.GoExpr)
+	case *ir.BinOp:
+		Expression(code, v.Left)
+		code.Write(fmt.Sprintf(" %s ", v.Op))
+		Expression(code, v.Right)
+	case *ir.Call:
+		Expression(code, v.Callee)
+		code.Write("([]Object{")
+		for i, a := range v.Args {
+			Expression(code, a)
+			if i != len(v.Args)-1 {
+				code.Write(", ")
+			}
+		}
+		code.Write("})")
+	case *ir.Store:
+		Expression(code, v.Target)
+		code.Write(" = ")
+		Expression(code, v.Value)
+	default:
+		panic(fmt.Sprintf("emit: unsupported node type %T", n))
+	}
+}
+
+// Statement renders n as one or more Go statement lines.
+func Statement(code *source.Code, n ir.Node) {
+	switch v := n.(type) {
+	case *ir.If:
+		code.Write("if runtime.ToBoolean(")
+		Expression(code, v.Cond)
+		code.WriteLine(") {")
+		for _, s := range v.Then {
+			Statement(code, s)
+		}
+		if len(v.Else) > 0 {
+			code.WriteLine("} else {")
+			for _, s := range v.Else {
+				Statement(code, s)
+			}
+		}
+		code.WriteLine("}")
+	case *ir.Loop:
+		code.Write("for ")
+		if v.Cond != nil {
+			code.Write("runtime.ToBoolean(")
+			Expression(code, v.Cond)
+			code.Write(")")
+		} else {
+			code.Write("true")
+		}
+		code.WriteLine(" {")
+		for _, s := range v.Body {
+			Statement(code, s)
+		}
+		code.WriteLine("}")
+	default:
+		Expression(code, v)
+		code.WriteLine("")
+	}
+}
+
+func constLiteral(c *ir.Const) string {
+	switch c.Kind {
+	case ir.ConstString:
+		// strconv.Quote round-trips quotes, backslashes, control characters
+		// and non-ASCII runes correctly; the old `"%s"` Sprintf didn't.
+		return fmt.Sprintf("JSString(%s)", strconv.Quote(c.Str))
+	default:
+		return fmt.Sprintf("JSNumber(%s)", numberLiteral(c.Num))
+	}
+}
+
+// numberLiteral renders a float64 as Go source. Go constant overflow rules
+// mean +Inf/-Inf/NaN can't be spelled as ordinary float literals (e.g.
+// `1e400` is a compile error, not +Inf), so those go through the math
+// package instead. Everything else uses strconv's shortest round-trippable
+// form, which - unlike the old `%f` - doesn't truncate large integers to six
+// decimal places of precision.
+func numberLiteral(n float64) string {
+	switch {
+	case math.IsNaN(n):
+		return "math.NaN()"
+	case math.IsInf(n, 1):
+		return "math.Inf(1)"
+	case math.IsInf(n, -1):
+		return "math.Inf(-1)"
+	default:
+		return strconv.FormatFloat(n, 'g', -1, 64)
+	}
+}
@@ -0,0 +1,73 @@
+package compiler
+
+import (
+	"fmt"
+
+	"github.com/srax47/godzilla/ast"
+)
+
+// compileFunctionDeclaration only mirrors the function onto the shared
+// global object when the declaration itself is at the script top level - a
+// function declared inside another function, or inside a module (see
+// inModule), must stay local to its own Go closure (see
+// compileVariableDeclarator for the same rule for `var`).
+func (c *compiler) compileFunctionDeclaration(fd *ast.FunctionDeclaration) {
+	name := fd.ID.Name
+	atTopLevel := !c.inFunction && !c.inModule
+
+	c.code.WriteLine(fmt.Sprintf("var %s Object", name))
+	c.code.Write(fmt.Sprintf("%s = ", name))
+	c.compileFunctionLiteral(fd.Params, fd.Body)
+	c.code.WriteLine("")
+	if atTopLevel {
+		c.code.Write(fmt.Sprintf(`global.DefineProperty("%s", %s)`, name, name))
+		c.code.WriteLine("")
+	}
+}
+
+func (c *compiler) compileFunctionExpression(fe *ast.FunctionExpression) {
+	c.compileFunctionLiteral(fe.Params, fe.Body)
+}
+
+func (c *compiler) compileArrowFunctionExpression(af *ast.ArrowFunctionExpression) {
+	c.compileFunctionLiteral(af.Params, af.Body)
+}
+
+// compileFunctionLiteral emits a Go closure backing a JS function value.
+// Go closures capture enclosing locals by reference already, which is
+// exactly what Free bindings need (see compileIdentifier in compiler.go) -
+// there's no separate cell type to allocate or thread through.
+func (c *compiler) compileFunctionLiteral(params []*ast.Identifier, body ast.Statement) {
+	c.code.Write("runtime.NewNativeFunction(func(args []Object) Object {")
+	for idx, p := range params {
+		c.code.WriteLine(fmt.Sprintf("var %s Object", p.Name))
+		c.code.WriteLine(fmt.Sprintf("if len(args) > %d {", idx))
+		c.code.WriteLine(fmt.Sprintf("%s = args[%d]", p.Name, idx))
+		c.code.WriteLine("}")
+	}
+
+	// Everything from here down is the function's own body, so any
+	// declaration inside it (see compileVariableDeclarator,
+	// compileFunctionDeclaration) must stay local rather than leaking onto
+	// the shared global object.
+	prevInFunction := c.inFunction
+	c.inFunction = true
+
+	if block, ok := body.(*ast.BlockStatement); ok {
+		c.writeHoistedVars(block.Body)
+		for _, s := range block.Body {
+			c.writeLineNo(s)
+			c.compileStatement(s)
+		}
+	} else {
+		// Arrow functions with an expression body implicitly return it.
+		c.code.Write("return ")
+		c.compileExpression(body.(ast.Expression))
+		c.code.WriteLine("")
+	}
+
+	c.inFunction = prevInFunction
+
+	c.code.WriteLine("return JSUndefined")
+	c.code.Write("})")
+}
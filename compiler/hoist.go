@@ -0,0 +1,83 @@
+package compiler
+
+import (
+	"fmt"
+
+	"github.com/srax47/godzilla/ast"
+)
+
+// hoistedVarNames collects every `var` binding introduced anywhere in body
+// without crossing into a nested function boundary - i.e. exactly the names
+// resolver.go now declares on the enclosing function scope. JS hoists `var`
+// declarations to the top of the nearest function regardless of how many
+// if/loop/switch blocks they're nested inside, so the Go variable backing
+// each one has to be declared once at function entry; compileVariableDeclarator
+// then only ever assigns to it, never redeclares it, so a block's synthetic
+// Go `{ }` going out of scope can't take the variable down with it.
+func hoistedVarNames(body []ast.Statement) []string {
+	seen := map[string]bool{}
+	var names []string
+
+	var walkStatement func(ast.Statement)
+	walkStatement = func(s ast.Statement) {
+		switch v := s.(type) {
+		case *ast.VariableDeclaration:
+			for _, d := range v.Declarations {
+				if !seen[d.ID.Name] {
+					seen[d.ID.Name] = true
+					names = append(names, d.ID.Name)
+				}
+			}
+		case *ast.BlockStatement:
+			for _, st := range v.Body {
+				walkStatement(st)
+			}
+		case *ast.IfStatement:
+			walkStatement(v.Consequent)
+			if v.Alternate != nil {
+				walkStatement(v.Alternate)
+			}
+		case *ast.WhileStatement:
+			walkStatement(v.Body)
+		case *ast.DoWhileStatement:
+			walkStatement(v.Body)
+		case *ast.ForStatement:
+			if vd, ok := v.Init.(*ast.VariableDeclaration); ok {
+				walkStatement(vd)
+			}
+			walkStatement(v.Body)
+		case *ast.ForInStatement:
+			if vd, ok := v.Left.(*ast.VariableDeclaration); ok {
+				walkStatement(vd)
+			}
+			walkStatement(v.Body)
+		case *ast.SwitchStatement:
+			for _, cs := range v.Cases {
+				for _, st := range cs.Consequent {
+					walkStatement(st)
+				}
+			}
+		case *ast.ExportNamedDeclaration:
+			if v.Declaration != nil {
+				walkStatement(v.Declaration)
+			}
+		}
+		// FunctionDeclaration (and any nested function/arrow expression) has
+		// its own scope, so we never descend into one.
+	}
+
+	for _, s := range body {
+		walkStatement(s)
+	}
+
+	return names
+}
+
+// writeHoistedVars declares every hoisted var up front, as a single `var
+// name Object` per name, before body's statements are compiled.
+func (c *compiler) writeHoistedVars(body []ast.Statement) {
+	for _, name := range hoistedVarNames(body) {
+		c.code.WriteLine(fmt.Sprintf("var %s Object", name))
+		c.code.WriteLine(fmt.Sprintf("_ = %s", name))
+	}
+}
@@ -0,0 +1,119 @@
+package ir
+
+import "strings"
+
+// Optimize runs the IR's optimization passes - currently constant folding
+// and dead-code elimination - and returns the rewritten node list. It's the
+// first pass added to prove the IR/emit split is worth the indirection: both
+// rewrites are impossible to do reliably against raw Go source strings, but
+// are a straightforward tree rewrite against typed nodes.
+func Optimize(nodes []Node) []Node {
+	folded := make([]Node, 0, len(nodes))
+	for _, n := range nodes {
+		folded = append(folded, Fold(n))
+	}
+	return eliminateDeadCode(folded)
+}
+
+// Fold recursively constant-folds a single node - most usefully BinOp nodes
+// whose operands are both numeric constants, e.g. `2 + 3` compiled from
+// source becomes the single node `Const{Num: 5}` instead of a runtime
+// addition. Unlike Optimize, Fold never drops the node, so it's safe to call
+// on an expression that's about to be used as a value rather than a
+// statement.
+func Fold(n Node) Node {
+	switch v := n.(type) {
+	case *BinOp:
+		left := Fold(v.Left)
+		right := Fold(v.Right)
+		if folded, ok := foldBinOp(v.Op, left, right); ok {
+			return folded
+		}
+		return &BinOp{Op: v.Op, Left: left, Right: right}
+	case *Call:
+		args := make([]Node, len(v.Args))
+		for i, a := range v.Args {
+			args[i] = Fold(a)
+		}
+		return &Call{Callee: Fold(v.Callee), Args: args}
+	case *Store:
+		return &Store{Target: v.Target, Value: Fold(v.Value)}
+	case *If:
+		return &If{Cond: Fold(v.Cond), Then: Optimize(v.Then), Else: Optimize(v.Else)}
+	case *Loop:
+		var cond Node
+		if v.Cond != nil {
+			cond = Fold(v.Cond)
+		}
+		return &Loop{Cond: cond, Body: Optimize(v.Body)}
+	default:
+		return n
+	}
+}
+
+func foldBinOp(op string, left, right Node) (Node, bool) {
+	l, ok := left.(*Const)
+	if !ok || l.Kind != ConstNumber {
+		return nil, false
+	}
+	r, ok := right.(*Const)
+	if !ok || r.Kind != ConstNumber {
+		return nil, false
+	}
+
+	switch op {
+	case "+":
+		return &Const{Kind: ConstNumber, Num: l.Num + r.Num}, true
+	case "-":
+		return &Const{Kind: ConstNumber, Num: l.Num - r.Num}, true
+	case "*":
+		return &Const{Kind: ConstNumber, Num: l.Num * r.Num}, true
+	case "/":
+		if r.Num == 0 {
+			return nil, false
+		}
+		return &Const{Kind: ConstNumber, Num: l.Num / r.Num}, true
+	default:
+		return nil, false
+	}
+}
+
+// eliminateDeadCode drops statement-position nodes that are provably
+// side-effect free - a bare constant or variable read used as its own
+// statement (e.g. what `x;` or `2 + 2;` as a standalone ExpressionStatement
+// folds down to) can never observably affect the program, so it's dropped
+// rather than emitted.
+func eliminateDeadCode(nodes []Node) []Node {
+	kept := make([]Node, 0, len(nodes))
+	for _, n := range nodes {
+		if isSideEffectFree(n) {
+			continue
+		}
+		kept = append(kept, n)
+	}
+	return kept
+}
+
+func isSideEffectFree(n Node) bool {
+	switch v := n.(type) {
+	case *Const:
+		return true
+	case *Load:
+		// Only a bare Go identifier is a plain variable read; a member read
+		// (runtime.GetMember(...), obj.prop) or an import dereference is a
+		// runtime operation like a global lookup and is kept.
+		return !isGlobalLookup(v.GoExpr) && !strings.ContainsAny(v.GoExpr, ".()")
+	default:
+		return false
+	}
+}
+
+// isGlobalLookup reports whether goExpr is the global.GetProperty(...) call
+// identifierGoExpr (see compiler/lower.go) falls back to for an unresolved
+// or genuinely global binding. Unlike a bare local/free variable reference,
+// that's a runtime property lookup - it can panic (or, with getters, run
+// arbitrary code) - so unlike a plain Load it's never safe for
+// eliminateDeadCode to drop.
+func isGlobalLookup(goExpr string) bool {
+	return strings.HasPrefix(goExpr, "global.GetProperty(")
+}
@@ -0,0 +1,26 @@
+package ir
+
+import "testing"
+
+// A global property lookup can panic (unresolved name) or run a getter
+// (arbitrary code), so it can never be treated as side-effect free just
+// because it surfaces as a Load.
+func TestOptimizeKeepsGlobalLookup(t *testing.T) {
+	nodes := []Node{&Load{GoExpr: `global.GetProperty("maybeUndefined")`}}
+
+	kept := Optimize(nodes)
+
+	if len(kept) != 1 {
+		t.Fatalf("expected the global lookup to survive dead-code elimination, got %d nodes kept", len(kept))
+	}
+}
+
+func TestOptimizeDropsLocalRead(t *testing.T) {
+	nodes := []Node{&Load{GoExpr: "x"}}
+
+	kept := Optimize(nodes)
+
+	if len(kept) != 0 {
+		t.Fatalf("expected a bare local read used as a statement to be dropped, got %d nodes kept", len(kept))
+	}
+}
@@ -0,0 +1,87 @@
+// Package ir is the compiler's intermediate representation: a small set of
+// typed nodes produced by the front end (package compiler) and consumed by a
+// backend (package emit generates Go source; a bytecode VM or WASM backend
+// could consume the same nodes without touching the front end). This mirrors
+// the split neo-go's compiler draws between AST analysis and opcode
+// emission, and replaces ad hoc string concatenation with nodes that can be
+// inspected and rewritten - e.g. by the constant-folding pass in fold.go -
+// before anything is turned into text.
+package ir
+
+// Node is any IR node. It carries no behavior of its own; backends type
+// switch on the concrete node types below.
+type Node interface {
+	irNode()
+}
+
+// ConstKind distinguishes the JS literal kinds Const can hold. Keeping the
+// raw value (rather than pre-rendered Go source) is what lets fold.go do
+// real arithmetic constant folding instead of string surgery.
+type ConstKind int
+
+const (
+	ConstNumber ConstKind = iota
+	ConstString
+)
+
+// Const is a literal value, not yet rendered to Go source.
+type Const struct {
+	Kind ConstKind
+	Num  float64
+	Str  string
+}
+
+// Load reads a previously bound Go variable (a Local or Free binding - see
+// compiler.binding), a global property by name, or any other value the front
+// end renders as a ready-made Go expression.
+type Load struct {
+	// GoExpr is the exact Go expression to read: a bare identifier for a
+	// bound variable, a global.GetProperty("name") call, a `(*name)`
+	// dereference for an imported binding, a member read (obj.prop or
+	// runtime.GetMember(obj, key)), or a builtin/runtime function name.
+	GoExpr string
+}
+
+// Store assigns Value to the Go variable or member expression Target emits.
+type Store struct {
+	Target Node
+	Value  Node
+}
+
+// BinOp is a binary operation with a Go operator token (e.g. "+", "===").
+type BinOp struct {
+	Op    string
+	Left  Node
+	Right Node
+}
+
+// Call invokes Callee with Args, mirroring the runtime calling convention of
+// `callee([]Object{args...})`.
+type Call struct {
+	Callee Node
+	Args   []Node
+}
+
+// If is a conditional with an optional Else branch. Cond is wrapped in
+// runtime.ToBoolean by the emitter, matching the codegen the compiler
+// package itself already does for JS truthiness.
+type If struct {
+	Cond Node
+	Then []Node
+	Else []Node
+}
+
+// Loop is a generic pre-test loop: `for Cond { Body }`. A nil Cond loops
+// forever (emitted as `for true`).
+type Loop struct {
+	Cond Node
+	Body []Node
+}
+
+func (*Const) irNode() {}
+func (*Load) irNode()  {}
+func (*Store) irNode() {}
+func (*BinOp) irNode() {}
+func (*Call) irNode()  {}
+func (*If) irNode()    {}
+func (*Loop) irNode()  {}
@@ -0,0 +1,84 @@
+package compiler
+
+import (
+	"github.com/srax47/godzilla/ast"
+	"github.com/srax47/godzilla/compiler/ir"
+)
+
+// canLowerExpression reports whether e is made up entirely of literals,
+// identifiers, binary operations and calls - a subset of the forms
+// lowerExpression handles (see lower.go); member accesses and template
+// literals report false. It never calls lowerExpression and must never panic, since
+// compileIfStatement/compileWhileStatement use it to decide, before writing
+// anything, whether to take the IR path or fall back to the direct-to-Go-
+// source path the rest of statements.go still uses.
+func canLowerExpression(e ast.Expression) bool {
+	switch v := e.(type) {
+	case *ast.StringLiteral, *ast.NumericLiteral, *ast.Identifier:
+		return true
+	case *ast.BinaryExpression:
+		return canLowerExpression(v.Left) && canLowerExpression(v.Right)
+	case *ast.CallExpression:
+		if !canLowerExpression(v.Callee) {
+			return false
+		}
+		for _, a := range v.Arguments {
+			if !canLowerExpression(a) {
+				return false
+			}
+		}
+		return true
+	default:
+		return false
+	}
+}
+
+// lowerStatement attempts to turn s into an IR node, mirroring lowerExpression
+// but for statements. Only a subset of statement forms have an IR
+// equivalent so far (a bare lowerable expression, or an assignment to a
+// plain identifier) - anything else returns ok=false and the caller falls
+// back to the direct-to-Go-source path, the same incremental-migration
+// approach lowerExpression already established for expressions.
+func (c *compiler) lowerStatement(s ast.Statement) (node ir.Node, ok bool) {
+	es, isExprStmt := s.(*ast.ExpressionStatement)
+	if !isExprStmt {
+		return nil, false
+	}
+
+	if ae, isAssign := es.Expression.(*ast.AssignmentExpression); isAssign {
+		id, isIdent := ae.Left.(*ast.Identifier)
+		if !isIdent || ae.Operator != "=" || !canLowerExpression(ae.Right) {
+			return nil, false
+		}
+		return &ir.Store{Target: &ir.Load{GoExpr: c.identifierGoExpr(id)}, Value: c.lowerExpression(ae.Right)}, true
+	}
+
+	if !canLowerExpression(es.Expression) {
+		return nil, false
+	}
+	return c.lowerExpression(es.Expression), true
+}
+
+// lowerBody lowers every statement making up a (possibly brace-less)
+// statement body, failing as soon as one statement doesn't have an IR
+// equivalent yet.
+func (c *compiler) lowerBody(s ast.Statement) ([]ir.Node, bool) {
+	block, ok := s.(*ast.BlockStatement)
+	if !ok {
+		n, ok := c.lowerStatement(s)
+		if !ok {
+			return nil, false
+		}
+		return []ir.Node{n}, true
+	}
+
+	nodes := make([]ir.Node, 0, len(block.Body))
+	for _, st := range block.Body {
+		n, ok := c.lowerStatement(st)
+		if !ok {
+			return nil, false
+		}
+		nodes = append(nodes, n)
+	}
+	return nodes, true
+}
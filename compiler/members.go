@@ -0,0 +1,275 @@
+package compiler
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/srax47/godzilla/ast"
+)
+
+// compileMemberExpression reads a property. Non-computed access (`a.b`) kept
+// its existing behaviour (including the builtin-function fast path);
+// computed access (`a[b]`) goes through runtime.GetMember, which is
+// responsible for the ToPropertyKey coercion JS applies to the bracket.
+func (c *compiler) compileMemberExpression(me *ast.MemberExpression) {
+	if !me.Computed {
+		if builtInFunc := c.getBuiltinFunc(me.Object, me.Property); builtInFunc != "" {
+			c.code.Write(builtInFunc)
+			return
+		}
+		c.compileExpression(me.Object)
+		c.code.Write(".")
+		c.compileExpression(me.Property)
+		return
+	}
+
+	c.code.Write("runtime.GetMember(")
+	c.compileExpression(me.Object)
+	c.code.Write(", ")
+	c.compileExpression(me.Property)
+	c.code.Write(")")
+}
+
+// compileAssignmentExpression special-cases a computed member target, since
+// `a[b] = c` has to become a runtime.SetMember call rather than a plain Go
+// `=`. Compound assignment (`+=` etc.) to a computed member isn't handled
+// yet - it would need the key expression evaluated once and reused for both
+// the read and the write to avoid double side effects, which is its own
+// follow-up.
+func (c *compiler) compileAssignmentExpression(ae *ast.AssignmentExpression) {
+	if me, ok := ae.Left.(*ast.MemberExpression); ok && me.Computed {
+		if ae.Operator != "=" {
+			panic("compound assignment to a computed member is not yet supported")
+		}
+		c.code.Write("runtime.SetMember(")
+		c.compileExpression(me.Object)
+		c.code.Write(", ")
+		c.compileExpression(me.Property)
+		c.code.Write(", ")
+		c.compileExpression(ae.Right)
+		c.code.Write(")")
+		return
+	}
+
+	c.compileExpression(ae.Left)
+	c.code.Write(fmt.Sprintf(" %s ", ae.Operator))
+	c.compileExpression(ae.Right)
+}
+
+// compileArrayExpression builds a runtime array. A plain composite literal
+// covers the common case; a spread element (`[...xs, y]`) forces building
+// the slice incrementally, since Go composite literals can't mix `elem...`
+// with ordinary elements the way a function call's variadic args can. A nil
+// element is an elision hole (`[1, , 3]`, valid JS) rather than a missing
+// AST node, and compiles to JSUndefined same as an explicit `undefined`.
+func (c *compiler) compileArrayExpression(ae *ast.ArrayExpression) {
+	if !anySpread(ae.Elements) {
+		c.code.Write("runtime.NewJSArray([]Object{")
+		for i, el := range ae.Elements {
+			c.compileArrayElement(el)
+			if i != len(ae.Elements)-1 {
+				c.code.Write(", ")
+			}
+		}
+		c.code.Write("})")
+		return
+	}
+
+	c.code.Write("func() Object {")
+	tmp := c.nextTempVar("arr")
+	c.code.WriteLine(fmt.Sprintf("%s := []Object{}", tmp))
+	for _, el := range ae.Elements {
+		if sp, ok := el.(*ast.SpreadElement); ok {
+			c.code.Write(fmt.Sprintf("%s = append(%s, runtime.ToSlice(", tmp, tmp))
+			c.compileExpression(sp.Argument)
+			c.code.WriteLine(")...)")
+			continue
+		}
+		c.code.Write(fmt.Sprintf("%s = append(%s, ", tmp, tmp))
+		c.compileArrayElement(el)
+		c.code.WriteLine(")")
+	}
+	c.code.WriteLine(fmt.Sprintf("return runtime.NewJSArray(%s)", tmp))
+	c.code.Write("}()")
+}
+
+func (c *compiler) compileArrayElement(el ast.Expression) {
+	if el == nil {
+		c.code.Write("JSUndefined")
+		return
+	}
+	c.compileExpression(el)
+}
+
+func anySpread(elements []ast.Expression) bool {
+	for _, el := range elements {
+		if _, ok := el.(*ast.SpreadElement); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// compileObjectExpression mirrors compileArrayExpression: a plain map
+// composite literal unless a spread property forces incremental building.
+func (c *compiler) compileObjectExpression(oe *ast.ObjectExpression) {
+	if !anyPropertySpread(oe.Properties) {
+		c.code.Write("runtime.NewJSObject(map[string]Object{")
+		for i, p := range oe.Properties {
+			c.writeObjectEntry(p.(*ast.Property), ": ")
+			if i != len(oe.Properties)-1 {
+				c.code.Write(", ")
+			}
+		}
+		c.code.Write("})")
+		return
+	}
+
+	c.code.Write("func() Object {")
+	tmp := c.nextTempVar("obj")
+	c.code.WriteLine(fmt.Sprintf("%s := map[string]Object{}", tmp))
+	for _, p := range oe.Properties {
+		if sp, ok := p.(*ast.SpreadElement); ok {
+			c.code.Write("for k, v := range runtime.ToMap(")
+			c.compileExpression(sp.Argument)
+			c.code.WriteLine(") {")
+			c.code.WriteLine(fmt.Sprintf("%s[k] = v", tmp))
+			c.code.WriteLine("}")
+			continue
+		}
+		c.code.Write(fmt.Sprintf("%s[", tmp))
+		c.writeObjectEntry(p.(*ast.Property), "] = ")
+		c.code.WriteLine("")
+	}
+	c.code.WriteLine(fmt.Sprintf("return runtime.NewJSObject(%s)", tmp))
+	c.code.Write("}()")
+}
+
+func anyPropertySpread(props []ast.Node) bool {
+	for _, p := range props {
+		if _, ok := p.(*ast.SpreadElement); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// writeObjectEntry writes a property's key followed by sep and its value -
+// shared between the `key: value` composite-literal form and the
+// `tmp[key] = value` incremental-assignment form.
+func (c *compiler) writeObjectEntry(p *ast.Property, sep string) {
+	if p.Computed {
+		c.code.Write("runtime.ToPropertyKey(")
+		c.compileExpression(p.Key)
+		c.code.Write(")")
+	} else {
+		c.code.Write(strconv.Quote(propertyKeyName(p.Key)))
+	}
+
+	c.code.Write(sep)
+
+	if p.Shorthand {
+		c.compileExpression(p.Key)
+		return
+	}
+	c.compileExpression(p.Value)
+}
+
+func propertyKeyName(key ast.Expression) string {
+	switch k := key.(type) {
+	case *ast.Identifier:
+		return k.Name
+	case *ast.StringLiteral:
+		return k.Value
+	default:
+		panic(fmt.Sprintf("unsupported object property key type %T", k))
+	}
+}
+
+// compileUpdateExpression lowers `++`/`--` (prefix and postfix) to a Go IIFE
+// so the construct stays usable as an expression (`y = x++`) and not just a
+// standalone statement. A member target's object is evaluated into a temp
+// once up front (preamble/target below) rather than re-rendered inline, so
+// an object expression with side effects (a call, another expression) is
+// only ever evaluated once, matching JS semantics.
+func (c *compiler) compileUpdateExpression(ue *ast.UpdateExpression) {
+	helper := "runtime.Increment"
+	if ue.Operator == "--" {
+		helper = "runtime.Decrement"
+	}
+
+	preamble, target := c.updateTarget(ue.Argument)
+	if ue.Prefix {
+		c.code.Write(fmt.Sprintf("func() Object { %s%s = %s(%s); return %s }()", preamble, target, helper, target, target))
+		return
+	}
+	c.code.Write(fmt.Sprintf("func() Object { %sold := %s; %s = %s(%s); return old }()", preamble, target, target, helper, target))
+}
+
+// updateTarget renders an UpdateExpression/assignment target as a Go lvalue
+// expression, returning an optional preamble statement (terminated by its
+// own "; ") that must be written before target is read or assigned.
+// Computed member targets aren't supported yet - same restriction as
+// compound assignment above.
+func (c *compiler) updateTarget(e ast.Expression) (preamble, target string) {
+	switch v := e.(type) {
+	case *ast.Identifier:
+		return "", c.identifierGoExpr(v)
+	case *ast.MemberExpression:
+		if v.Computed {
+			panic("update expression on a computed member is not yet supported")
+		}
+		objExpr := renderExpr(c.lowerExpression(v.Object))
+		objTmp := c.nextTempVar("obj")
+		propID := v.Property.(*ast.Identifier)
+		return fmt.Sprintf("%s := %s; ", objTmp, objExpr), fmt.Sprintf("%s.%s", objTmp, propID.Name)
+	default:
+		panic(fmt.Sprintf("unsupported update expression target %T", v))
+	}
+}
+
+// compileUnaryExpression covers JS's single-argument operators. `delete`
+// only makes sense applied to a member expression.
+func (c *compiler) compileUnaryExpression(ue *ast.UnaryExpression) {
+	switch ue.Operator {
+	case "!":
+		c.code.Write("runtime.Not(")
+		c.compileExpression(ue.Argument)
+		c.code.Write(")")
+	case "-":
+		c.code.Write("runtime.Negate(")
+		c.compileExpression(ue.Argument)
+		c.code.Write(")")
+	case "typeof":
+		c.code.Write("runtime.TypeOf(")
+		c.compileExpression(ue.Argument)
+		c.code.Write(")")
+	case "void":
+		c.code.Write("func() Object { ")
+		c.compileExpression(ue.Argument)
+		c.code.WriteLine("")
+		c.code.Write("return JSUndefined }()")
+	case "delete":
+		c.compileDeleteExpression(ue.Argument)
+	default:
+		panic("unsupported unary operator " + ue.Operator)
+	}
+}
+
+func (c *compiler) compileDeleteExpression(target ast.Expression) {
+	me, ok := target.(*ast.MemberExpression)
+	if !ok {
+		panic("delete target must be a member expression")
+	}
+
+	c.code.Write("runtime.DeleteMember(")
+	c.compileExpression(me.Object)
+	c.code.Write(", ")
+	if me.Computed {
+		c.compileExpression(me.Property)
+	} else {
+		propID := me.Property.(*ast.Identifier)
+		c.code.Write(fmt.Sprintf("JSString(%s)", strconv.Quote(propID.Name)))
+	}
+	c.code.Write(")")
+}
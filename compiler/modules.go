@@ -0,0 +1,163 @@
+package compiler
+
+import (
+	"fmt"
+
+	"github.com/srax47/godzilla/ast"
+	"github.com/srax47/godzilla/runtime"
+	"github.com/srax47/godzilla/source"
+)
+
+// CompileModule compiles a single source file as an ES module: the file body
+// is wrapped in a Go function that builds and returns a module record
+// (exported bindings keyed by name), rather than running at the top level of
+// `main` the way a plain script does via Compile. specifier is the resolved
+// module specifier this file was loaded under, used to register the record
+// with the ModuleLoader's cache so re-imports of the same module are linked
+// to the same instance rather than re-evaluated.
+//
+// The parser-side grammar for import/export (recognizing the `import`,
+// `export`, `from`, and `as` keywords and producing the AST nodes this file
+// compiles) lives in the parser package and isn't touched here.
+func CompileModule(f *ast.File, specifier string) *source.Code {
+	code := source.NewCode()
+
+	c := &compiler{
+		code:       code,
+		ctx:        runtime.NewDefaultContext(),
+		sourceFile: f.Filename,
+		inModule:   true,
+	}
+	res := newResolver()
+	res.resolveProgram(f.Program)
+	c.bindings = res.bindings
+	c.exports = map[string]string{}
+
+	c.code.WriteLine(fmt.Sprintf(`global.ModuleLoader.Define("%s", func(global *runtime.Environment) *runtime.ModuleRecord {`, specifier))
+	// exports is keyed to *Object, not Object, so recordExport can hand out
+	// the address of a module-level binding rather than a one-time value
+	// copy - see recordExport and compileImportDeclaration below for the
+	// importer side of the same mechanism.
+	c.code.WriteLine("exports := map[string]*Object{}")
+	c.writeHoistedVars(f.Program.Body)
+	for _, s := range f.Program.Body {
+		c.writeLineNo(s)
+		c.compileStatement(s)
+		c.code.WriteLine("")
+	}
+	c.code.WriteLine("return &runtime.ModuleRecord{Exports: exports}")
+	c.code.WriteLine("})")
+
+	return code
+}
+
+// compileImportDeclaration resolves the imported module up front (triggering
+// its module function if this is the first import of it) and binds each
+// local name to the *Object pointer stored in the exporting module's Exports
+// map (see recordExport), rather than copying the Object it currently points
+// to - so a later reassignment in the exporting module (`export let count =
+// 0; ... count++`) is visible through every importer, matching the ES live
+// binding semantics the doc comment here used to just assert without
+// actually implementing. Every reference to an imported name is resolved as
+// BindingImport (see resolver.go/scope.go) and rendered as a dereference
+// (see identifierGoExpr in lower.go) to go with it. A namespace import
+// (`import * as ns`) is a plain snapshot object, not a live binding target,
+// so it stays a bare value the same as before.
+func (c *compiler) compileImportDeclaration(id *ast.ImportDeclaration) {
+	modVar := c.nextTempVar("mod")
+	c.code.WriteLine(fmt.Sprintf(`%s := global.ModuleLoader.Load("%s")`, modVar, id.Source.Value))
+	c.code.WriteLine(fmt.Sprintf("if %s.Pending {", modVar))
+	c.code.WriteLine(fmt.Sprintf(`panic(runtime.NewCyclicImportError("%s"))`, id.Source.Value))
+	c.code.WriteLine("}")
+
+	for _, spec := range id.Specifiers {
+		switch s := spec.(type) {
+		case *ast.ImportDefaultSpecifier:
+			c.code.WriteLine(fmt.Sprintf(`%s := %s.Exports["default"]`, s.Local.Name, modVar))
+		case *ast.ImportSpecifier:
+			c.code.WriteLine(fmt.Sprintf(`%s := %s.Exports["%s"]`, s.Local.Name, modVar, s.Imported.Name))
+		case *ast.ImportNamespaceSpecifier:
+			c.code.WriteLine(fmt.Sprintf(`%s := runtime.NewNamespaceObject(%s.Exports)`, s.Local.Name, modVar))
+		}
+	}
+}
+
+// compileExportNamedDeclaration handles both `export const x = ...` (a
+// wrapped declaration) and `export { a, b as c }` (specifiers referring to
+// already-declared local bindings).
+func (c *compiler) compileExportNamedDeclaration(ed *ast.ExportNamedDeclaration) {
+	if ed.Declaration != nil {
+		c.compileStatement(ed.Declaration)
+		for _, name := range c.exportedNames(ed.Declaration) {
+			c.recordExport(name, name)
+		}
+	}
+
+	for _, spec := range ed.Specifiers {
+		c.recordExport(spec.Exported.Name, spec.Local.Name)
+	}
+}
+
+func (c *compiler) compileExportDefaultDeclaration(ed *ast.ExportDefaultDeclaration) {
+	switch v := ed.Declaration.(type) {
+	case ast.Expression:
+		tmp := c.nextTempVar("default")
+		c.code.Write(fmt.Sprintf("%s := ", tmp))
+		c.compileExpression(v)
+		c.code.WriteLine("")
+		c.recordExport("default", tmp)
+	case *ast.FunctionDeclaration:
+		c.compileFunctionDeclaration(v)
+		c.recordExport("default", v.ID.Name)
+	default:
+		panic("unsupported export default declaration")
+	}
+}
+
+// compileExportAllDeclaration re-exports every binding of another module,
+// optionally under a single namespace name (`export * as ns from "./m"`).
+func (c *compiler) compileExportAllDeclaration(ed *ast.ExportAllDeclaration) {
+	modVar := c.nextTempVar("mod")
+	c.code.WriteLine(fmt.Sprintf(`%s := global.ModuleLoader.Load("%s")`, modVar, ed.Source.Value))
+	if ed.Exported != nil {
+		nsVar := c.nextTempVar("ns")
+		c.code.WriteLine(fmt.Sprintf(`%s := runtime.NewNamespaceObject(%s.Exports)`, nsVar, modVar))
+		c.code.WriteLine(fmt.Sprintf(`exports["%s"] = &%s`, ed.Exported.Name, nsVar))
+		return
+	}
+	c.code.WriteLine(fmt.Sprintf("for name, value := range %s.Exports {", modVar))
+	c.code.WriteLine(`exports[name] = value`)
+	c.code.WriteLine("}")
+}
+
+// recordExport hands out the address of the already-declared local variable
+// backing exportedName, not a copy of its current value, so the map entry
+// keeps reflecting localName's value even after the exporting module
+// reassigns it post-export (e.g. `export let count = 0;` followed later by
+// `count++`). This assumes localName is itself an addressable Go local
+// (the hoisted module-level var, a `tmp :=` default-export binding, or an
+// already-declared `export { a, b as c }` specifier) - re-exporting an
+// imported name itself (whose local is already a *Object) isn't handled by
+// this helper and would need its own case if a request ever needs it.
+func (c *compiler) recordExport(exportedName, localName string) {
+	c.exports[exportedName] = localName
+	c.code.WriteLine(fmt.Sprintf(`exports["%s"] = &%s`, exportedName, localName))
+}
+
+// exportedNames returns the binding names a wrapped export declaration
+// introduces, so `export const x = 1, y = 2` exports both x and y, and
+// `export function f() {}` exports f.
+func (c *compiler) exportedNames(s ast.Statement) []string {
+	if fd, ok := s.(*ast.FunctionDeclaration); ok {
+		return []string{fd.ID.Name}
+	}
+	vd, ok := s.(*ast.VariableDeclaration)
+	if !ok {
+		return nil
+	}
+	names := make([]string, 0, len(vd.Declarations))
+	for _, d := range vd.Declarations {
+		names = append(names, d.ID.Name)
+	}
+	return names
+}
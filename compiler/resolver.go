@@ -0,0 +1,244 @@
+package compiler
+
+import "github.com/srax47/godzilla/ast"
+
+// resolver performs a single pass over the AST before codegen, walking the
+// same scope chain the compiled Go closures will walk at runtime, and
+// records a binding kind for every identifier reference it sees. This is
+// the same shape as starlark-go's resolver: classify each name once
+// (Local/Free/Global) ahead of time so compileIdentifier doesn't need to
+// re-derive it, and so the FIXME global-context hack this replaced is gone
+// for good.
+type resolver struct {
+	bindings map[*ast.Identifier]binding
+	scope    *Scope
+}
+
+func newResolver() *resolver {
+	return &resolver{
+		bindings: map[*ast.Identifier]binding{},
+		scope:    newScope(nil, true),
+	}
+}
+
+func (r *resolver) resolveProgram(p *ast.Program) {
+	for _, s := range p.Body {
+		r.resolveStatement(s)
+	}
+}
+
+func (r *resolver) resolveStatement(s ast.Statement) {
+	switch v := s.(type) {
+	case *ast.VariableDeclaration:
+		for _, d := range v.Declarations {
+			if d.Init != nil {
+				r.resolveExpression(d.Init)
+			}
+			// JS `var` is function-scoped, not block-scoped: it hoists to
+			// the nearest enclosing function (or the Program itself), so it
+			// has to be declared there rather than in whatever block scope
+			// we're currently resolving - otherwise a `var` declared inside
+			// an if/loop/switch body is gone (and misresolves as a global)
+			// the moment that block's scope is popped. See hoistedVarNames
+			// in hoist.go, which the matching codegen uses to keep this in
+			// sync with where the Go variable actually gets declared.
+			r.scope.enclosingFunc().declare(d.ID.Name)
+		}
+	case *ast.ExpressionStatement:
+		r.resolveExpression(v.Expression)
+	case *ast.BlockStatement:
+		r.withScope(false, func() {
+			for _, st := range v.Body {
+				r.resolveStatement(st)
+			}
+		})
+	case *ast.IfStatement:
+		r.resolveExpression(v.Test)
+		r.resolveStatement(v.Consequent)
+		if v.Alternate != nil {
+			r.resolveStatement(v.Alternate)
+		}
+	case *ast.WhileStatement:
+		r.resolveExpression(v.Test)
+		r.resolveStatement(v.Body)
+	case *ast.DoWhileStatement:
+		r.resolveStatement(v.Body)
+		r.resolveExpression(v.Test)
+	case *ast.ForStatement:
+		r.withScope(false, func() {
+			switch init := v.Init.(type) {
+			case *ast.VariableDeclaration:
+				r.resolveStatement(init)
+			case ast.Expression:
+				r.resolveExpression(init)
+			}
+			if v.Test != nil {
+				r.resolveExpression(v.Test)
+			}
+			if v.Update != nil {
+				r.resolveExpression(v.Update)
+			}
+			r.resolveStatement(v.Body)
+		})
+	case *ast.ForInStatement:
+		r.withScope(false, func() {
+			r.resolveExpression(v.Right)
+			switch left := v.Left.(type) {
+			case *ast.VariableDeclaration:
+				r.scope.enclosingFunc().declare(left.Declarations[0].ID.Name)
+			case *ast.Identifier:
+				r.resolveIdentifier(left)
+			}
+			r.resolveStatement(v.Body)
+		})
+	case *ast.SwitchStatement:
+		r.resolveExpression(v.Discriminant)
+		for _, cs := range v.Cases {
+			if cs.Test != nil {
+				r.resolveExpression(cs.Test)
+			}
+			for _, st := range cs.Consequent {
+				r.resolveStatement(st)
+			}
+		}
+	case *ast.ReturnStatement:
+		if v.Argument != nil {
+			r.resolveExpression(v.Argument)
+		}
+	case *ast.FunctionDeclaration:
+		r.scope.declare(v.ID.Name)
+		r.resolveFunction(v.Params, v.Body)
+	case *ast.BreakStatement, *ast.ContinueStatement:
+		// no identifiers
+	case *ast.ImportDeclaration:
+		for _, spec := range v.Specifiers {
+			switch s := spec.(type) {
+			case *ast.ImportDefaultSpecifier:
+				r.scope.declareImport(s.Local.Name)
+			case *ast.ImportSpecifier:
+				r.scope.declareImport(s.Local.Name)
+			case *ast.ImportNamespaceSpecifier:
+				// A namespace import is a plain snapshot object, not a live
+				// pointer binding (see compileImportDeclaration) - declare
+				// it like an ordinary local.
+				r.scope.declare(s.Local.Name)
+			}
+		}
+	case *ast.ExportNamedDeclaration:
+		if v.Declaration != nil {
+			r.resolveStatement(v.Declaration)
+		}
+		for _, spec := range v.Specifiers {
+			r.resolveIdentifier(spec.Local)
+		}
+	case *ast.ExportDefaultDeclaration:
+		switch d := v.Declaration.(type) {
+		case ast.Expression:
+			r.resolveExpression(d)
+		case *ast.FunctionDeclaration:
+			r.scope.declare(d.ID.Name)
+			r.resolveFunction(d.Params, d.Body)
+		}
+	case *ast.ExportAllDeclaration:
+		// Source is a string literal - no identifiers to resolve.
+	}
+}
+
+func (r *resolver) resolveExpression(e ast.Expression) {
+	switch v := e.(type) {
+	case *ast.Identifier:
+		r.resolveIdentifier(v)
+	case *ast.CallExpression:
+		r.resolveExpression(v.Callee)
+		for _, a := range v.Arguments {
+			r.resolveExpression(a)
+		}
+	case *ast.AssignmentExpression:
+		r.resolveExpression(v.Left)
+		r.resolveExpression(v.Right)
+	case *ast.BinaryExpression:
+		r.resolveExpression(v.Left)
+		r.resolveExpression(v.Right)
+	case *ast.MemberExpression:
+		r.resolveExpression(v.Object)
+		if v.Computed {
+			r.resolveExpression(v.Property)
+		}
+	case *ast.FunctionExpression:
+		r.resolveFunction(v.Params, v.Body)
+	case *ast.ArrowFunctionExpression:
+		r.resolveFunction(v.Params, v.Body)
+	case *ast.TemplateLiteral:
+		for _, expr := range v.Expressions {
+			r.resolveExpression(expr)
+		}
+	case *ast.ArrayExpression:
+		for _, el := range v.Elements {
+			if el != nil {
+				r.resolveExpression(el)
+			}
+		}
+	case *ast.ObjectExpression:
+		for _, p := range v.Properties {
+			switch prop := p.(type) {
+			case *ast.Property:
+				if prop.Computed {
+					r.resolveExpression(prop.Key)
+				}
+				if prop.Shorthand {
+					r.resolveExpression(prop.Key)
+				} else {
+					r.resolveExpression(prop.Value)
+				}
+			case *ast.SpreadElement:
+				r.resolveExpression(prop.Argument)
+			}
+		}
+	case *ast.UpdateExpression:
+		r.resolveExpression(v.Argument)
+	case *ast.UnaryExpression:
+		r.resolveExpression(v.Argument)
+	case *ast.SpreadElement:
+		r.resolveExpression(v.Argument)
+	}
+}
+
+func (r *resolver) resolveIdentifier(i *ast.Identifier) {
+	owner := r.scope.resolve(i.Name)
+	switch {
+	case owner == nil:
+		r.bindings[i] = binding{kind: BindingGlobal, name: i.Name}
+	case owner.imports[i.Name]:
+		r.bindings[i] = binding{kind: BindingImport, name: i.Name}
+	case owner.enclosingFunc() == r.scope.enclosingFunc():
+		r.bindings[i] = binding{kind: BindingLocal, name: i.Name}
+	default:
+		r.bindings[i] = binding{kind: BindingFree, name: i.Name}
+	}
+}
+
+func (r *resolver) resolveFunction(params []*ast.Identifier, body ast.Statement) {
+	r.withScope(true, func() {
+		for _, p := range params {
+			r.scope.declare(p.Name)
+		}
+		if _, ok := body.(*ast.BlockStatement); ok {
+			r.resolveStatement(body)
+			return
+		}
+		// An arrow function with a concise (non-block) body has an
+		// expression here, not a statement - resolveStatement's switch has
+		// no case for any expression type, so without this branch every
+		// identifier referenced in the body (including the function's own
+		// parameters) silently resolved as a global. See compileFunctionLiteral
+		// in functions.go for the matching codegen branch.
+		r.resolveExpression(body.(ast.Expression))
+	})
+}
+
+func (r *resolver) withScope(isFunc bool, f func()) {
+	prev := r.scope
+	r.scope = newScope(prev, isFunc)
+	f()
+	r.scope = prev
+}
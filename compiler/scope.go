@@ -0,0 +1,72 @@
+package compiler
+
+// BindingKind classifies how an identifier reference resolves during
+// compilation: as a variable local to the enclosing function, a variable
+// captured from an outer function, an imported module binding backed by a
+// *Object pointer rather than a plain Object (see compileImportDeclaration
+// in modules.go), or a true global falling through to global.GetProperty.
+type BindingKind int
+
+const (
+	BindingGlobal BindingKind = iota
+	BindingLocal
+	BindingFree
+	BindingImport
+)
+
+// binding is what the resolve pre-pass (see resolver.go) records for a
+// single *ast.Identifier reference.
+type binding struct {
+	kind BindingKind
+	name string
+}
+
+// Scope is one link in the lexical scope chain built by the resolver. It
+// mirrors the scope the compiled Go code will itself have at runtime: a
+// function scope is a new Go closure, a block scope is just a nested Go
+// block that shadows names within it.
+type Scope struct {
+	parent  *Scope
+	isFunc  bool
+	vars    map[string]bool
+	imports map[string]bool
+}
+
+func newScope(parent *Scope, isFunc bool) *Scope {
+	return &Scope{parent: parent, isFunc: isFunc, vars: map[string]bool{}, imports: map[string]bool{}}
+}
+
+func (s *Scope) declare(name string) {
+	s.vars[name] = true
+}
+
+// declareImport is like declare, but also marks name as backed by a *Object
+// pointer (see compileImportDeclaration) rather than a plain Object, so
+// resolveIdentifier can classify references to it as BindingImport instead
+// of BindingLocal/BindingFree.
+func (s *Scope) declareImport(name string) {
+	s.vars[name] = true
+	s.imports[name] = true
+}
+
+// enclosingFunc walks up to the nearest function scope (or the outermost
+// scope if none is marked, i.e. the Program scope itself).
+func (s *Scope) enclosingFunc() *Scope {
+	for sc := s; sc != nil; sc = sc.parent {
+		if sc.isFunc || sc.parent == nil {
+			return sc
+		}
+	}
+	return s
+}
+
+// resolve walks up the chain looking for name, returning the scope that
+// declares it, or nil if it is never declared (i.e. it's a global).
+func (s *Scope) resolve(name string) *Scope {
+	for sc := s; sc != nil; sc = sc.parent {
+		if sc.vars[name] {
+			return sc
+		}
+	}
+	return nil
+}
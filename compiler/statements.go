@@ -0,0 +1,242 @@
+package compiler
+
+import (
+	"fmt"
+
+	"github.com/srax47/godzilla/ast"
+	"github.com/srax47/godzilla/compiler/emit"
+	"github.com/srax47/godzilla/compiler/ir"
+	"github.com/srax47/godzilla/utils"
+)
+
+// compileIfStatement emits a Go if/else. When the test and both branches are
+// made up entirely of statement forms lower_statement.go already knows how
+// to turn into IR, it goes through ir.If/ir.Fold/emit.Statement instead, so
+// the constant-folding pass added in compiler/ir actually gets exercised on
+// control flow, not just expressions - otherwise it falls back to writing
+// Go source directly, same as before. The test is wrapped in
+// runtime.ToBoolean either way so JS truthiness (0, "", null, undefined, NaN
+// are falsy) is applied rather than relying on Go's native bool conversion.
+func (c *compiler) compileIfStatement(is *ast.IfStatement) {
+	if node, ok := c.lowerIfStatement(is); ok {
+		emit.Statement(c.code, ir.Fold(node))
+		return
+	}
+
+	c.code.Write("if runtime.ToBoolean(")
+	c.compileExpression(is.Test)
+	c.code.WriteLine(") {")
+	c.compileStatement(is.Consequent)
+	if is.Alternate != nil {
+		c.code.WriteLine("} else {")
+		c.compileStatement(is.Alternate)
+	}
+	c.code.WriteLine("}")
+}
+
+func (c *compiler) lowerIfStatement(is *ast.IfStatement) (ir.Node, bool) {
+	if !canLowerExpression(is.Test) {
+		return nil, false
+	}
+	then, ok := c.lowerBody(is.Consequent)
+	if !ok {
+		return nil, false
+	}
+
+	var els []ir.Node
+	if is.Alternate != nil {
+		if els, ok = c.lowerBody(is.Alternate); !ok {
+			return nil, false
+		}
+	}
+
+	return &ir.If{Cond: c.lowerExpression(is.Test), Then: then, Else: els}, true
+}
+
+func (c *compiler) compileBlockStatement(bs *ast.BlockStatement) {
+	c.code.WriteLine("{")
+	for _, s := range bs.Body {
+		c.writeLineNo(s)
+		c.compileStatement(s)
+	}
+	c.code.WriteLine("}")
+}
+
+// compileWhileStatement takes the same IR-or-fallback approach as
+// compileIfStatement above.
+func (c *compiler) compileWhileStatement(ws *ast.WhileStatement) {
+	if canLowerExpression(ws.Test) {
+		if body, ok := c.lowerBody(ws.Body); ok {
+			emit.Statement(c.code, ir.Fold(&ir.Loop{Cond: c.lowerExpression(ws.Test), Body: body}))
+			return
+		}
+	}
+
+	c.code.Write("for runtime.ToBoolean(")
+	c.compileExpression(ws.Test)
+	c.code.WriteLine(") {")
+	c.compileStatement(ws.Body)
+	c.code.WriteLine("}")
+}
+
+// compileDoWhileStatement lowers do/while to Go's infinite for, with the
+// condition check moved to the bottom of the loop body since Go has no
+// native do-while construct.
+func (c *compiler) compileDoWhileStatement(dw *ast.DoWhileStatement) {
+	c.code.WriteLine("for {")
+	c.compileStatement(dw.Body)
+	c.code.Write("if !runtime.ToBoolean(")
+	c.compileExpression(dw.Test)
+	c.code.WriteLine(") {")
+	c.code.WriteLine("break")
+	c.code.WriteLine("}")
+	c.code.WriteLine("}")
+}
+
+// compileForStatement wraps the loop in its own Go block so the init clause
+// runs as ordinary multi-line statements ahead of the loop (for a `var`
+// init, compileVariableDeclaration's assignments to the already-hoisted
+// variables), rather than trying to cram it into Go's single-statement
+// for-init slot.
+func (c *compiler) compileForStatement(fs *ast.ForStatement) {
+	c.code.WriteLine("{")
+	if fs.Init != nil {
+		c.compileForInit(fs.Init)
+	}
+	c.code.Write("for ")
+	if fs.Test != nil {
+		c.code.Write("runtime.ToBoolean(")
+		c.compileExpression(fs.Test)
+		c.code.Write(")")
+	} else {
+		c.code.Write("true")
+	}
+	c.code.WriteLine(" {")
+	c.compileStatement(fs.Body)
+	if fs.Update != nil {
+		c.compileExpression(fs.Update)
+		c.code.WriteLine("")
+	}
+	c.code.WriteLine("}")
+	c.code.WriteLine("}")
+}
+
+func (c *compiler) compileForInit(n ast.Node) {
+	switch v := n.(type) {
+	case *ast.VariableDeclaration:
+		c.compileVariableDeclaration(v)
+		c.code.WriteLine("")
+	case ast.Expression:
+		c.compileExpression(v)
+		c.code.WriteLine("")
+	default:
+		panic("unknown for-init type " + utils.TypeOf(v))
+	}
+}
+
+// compileForInStatement enumerates the own property keys of the right-hand
+// object. runtime.EnumerateKeys returns []Object (each a JSString) so the
+// loop variable keeps the same Object type every other binding uses. The
+// range itself declares a fresh, loop-scoped Go variable via `:=` - that's
+// thrown away each iteration - and its value is assigned into the real
+// binding (the hoisted `var k Object` for `for (var k in obj)`, or the
+// resolved Local/Free binding for `for (x in obj)`) so the binding is still
+// live, and still reflects the last key enumerated, after the loop ends.
+func (c *compiler) compileForInStatement(fs *ast.ForInStatement) {
+	keyVar := c.nextTempVar("key")
+	target := c.forInBindingTarget(fs.Left)
+	c.code.Write(fmt.Sprintf("for _, %s := range runtime.EnumerateKeys(", keyVar))
+	c.compileExpression(fs.Right)
+	c.code.WriteLine(") {")
+	c.code.WriteLine(fmt.Sprintf("%s = %s", target, keyVar))
+	c.compileStatement(fs.Body)
+	c.code.WriteLine("}")
+}
+
+func (c *compiler) forInBindingTarget(n ast.Node) string {
+	switch v := n.(type) {
+	case *ast.VariableDeclaration:
+		// Already declared by writeHoistedVars - just the raw name, same as
+		// compileVariableDeclarator does for an ordinary assignment.
+		return v.Declarations[0].ID.Name
+	case *ast.Identifier:
+		return c.identifierGoExpr(v)
+	default:
+		panic("unsupported for-in binding " + utils.TypeOf(v))
+	}
+}
+
+func (c *compiler) compileBreakStatement(bs *ast.BreakStatement) {
+	if bs.Label != nil {
+		c.code.WriteLine(fmt.Sprintf("break %s", bs.Label.Name))
+		return
+	}
+	c.code.WriteLine("break")
+}
+
+func (c *compiler) compileContinueStatement(cs *ast.ContinueStatement) {
+	if cs.Label != nil {
+		c.code.WriteLine(fmt.Sprintf("continue %s", cs.Label.Name))
+		return
+	}
+	c.code.WriteLine("continue")
+}
+
+func (c *compiler) compileReturnStatement(rs *ast.ReturnStatement) {
+	if rs.Argument == nil {
+		c.code.WriteLine("return")
+		return
+	}
+	c.code.Write("return ")
+	c.compileExpression(rs.Argument)
+	c.code.WriteLine("")
+}
+
+// compileSwitchStatement lowers to a tag-less Go switch with one
+// runtime.StrictEquals case per JS case, since Go's own switch has reference-
+// equality and type-switch semantics rather than JS's strict equality. Cases
+// whose JS body doesn't end in `break` get an explicit Go `fallthrough`,
+// since (unlike JS) Go cases don't fall through by default.
+func (c *compiler) compileSwitchStatement(ss *ast.SwitchStatement) {
+	discVar := c.nextTempVar("switchDisc")
+	c.code.Write(fmt.Sprintf("%s := ", discVar))
+	c.compileExpression(ss.Discriminant)
+	c.code.WriteLine("")
+	c.code.WriteLine("switch {")
+	for i, cs := range ss.Cases {
+		if cs.Test != nil {
+			c.code.Write(fmt.Sprintf("case runtime.StrictEquals(%s, ", discVar))
+			c.compileExpression(cs.Test)
+			c.code.WriteLine("):")
+		} else {
+			c.code.WriteLine("default:")
+		}
+		c.compileSwitchCaseBody(cs, i == len(ss.Cases)-1)
+	}
+	c.code.WriteLine("}")
+}
+
+func (c *compiler) compileSwitchCaseBody(cs *ast.SwitchCase, isLast bool) {
+	stmts := cs.Consequent
+	endsInBreak := false
+	if n := len(stmts); n > 0 {
+		// An unlabeled break just ends the JS case - that's exactly what
+		// falling out of the Go case already does, so it's dropped rather
+		// than compiled. A labeled break (`break outer;`) means something
+		// else entirely (exiting an enclosing labeled loop), so it has to
+		// still be emitted via compileBreakStatement below.
+		if bs, ok := stmts[n-1].(*ast.BreakStatement); ok && bs.Label == nil {
+			endsInBreak = true
+			stmts = stmts[:n-1]
+		}
+	}
+
+	for _, s := range stmts {
+		c.writeLineNo(s)
+		c.compileStatement(s)
+	}
+
+	if !endsInBreak && !isLast {
+		c.code.WriteLine("fallthrough")
+	}
+}